@@ -0,0 +1,58 @@
+package cmd
+
+import (
+    "github.com/urfave/cli/v2"
+
+    "github.com/sug0/refman/internal/refman"
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+var addLogger = log.Component("cmd.add")
+
+func addCommand() *cli.Command {
+    var pdfFile, bibtexFile string
+
+    return &cli.Command{
+        Name:      "add",
+        Usage:     "add a PDF, and optionally its BibTeX reference, to the index",
+        ArgsUsage: " ",
+        Flags: []cli.Flag{
+            &cli.StringFlag{
+                Name:        "pdf",
+                Usage:       "The PDF file to parse.",
+                Destination: &pdfFile,
+                Required:    true,
+            },
+            &cli.StringFlag{
+                Name:        "bibtex",
+                Usage:       "The BibTeX file to parse.",
+                Destination: &bibtexFile,
+            },
+        },
+        Action: func(c *cli.Context) error {
+            addLogger.Printf("Parsing PDF file: %s\n", pdfFile)
+            doc, err := refman.ParseDocument(pdfFile, bibtexFile)
+            if err != nil {
+                return err
+            }
+
+            index, err := openIndex()
+            if err != nil {
+                return err
+            }
+            defer index.Close()
+
+            addLogger.Println("Updating index file with new entry.")
+            if err := index.Index(doc.Path, doc); err != nil {
+                return err
+            }
+
+            manifest, err := openManifest()
+            if err != nil {
+                return err
+            }
+            manifest.Put(doc.Path, refman.Entry{PDFPath: doc.Path, BibtexPath: bibtexFile})
+            return manifest.Save()
+        },
+    }
+}
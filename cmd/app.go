@@ -0,0 +1,78 @@
+// Package cmd wires together the refman subcommands on top of
+// github.com/urfave/cli/v2, and holds the config setup that every
+// subcommand relies on.
+package cmd
+
+import (
+    "os"
+
+    "github.com/blevesearch/bleve"
+    "github.com/urfave/cli/v2"
+
+    "github.com/sug0/refman/internal/refman"
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+// this is set upon app startup, in setup()
+var cfg *refman.Config
+
+// cmd line flags
+var (
+    verbose    bool
+    configFile string
+)
+
+var logger = log.Component("cmd.app")
+
+// NewApp builds the refman command line application.
+func NewApp() *cli.App {
+    return &cli.App{
+        Name:  "refman",
+        Usage: "manage and search a local library of PDF references",
+        Flags: []cli.Flag{
+            &cli.BoolFlag{
+                Name:        "v",
+                Usage:       "Shortcut for DEBUG=*: print every component's logs to stderr.",
+                Destination: &verbose,
+            },
+            &cli.StringFlag{
+                Name:        "config",
+                Usage:       "Path to a config.yaml file.",
+                Destination: &configFile,
+            },
+        },
+        Before: setup,
+        Commands: []*cli.Command{
+            addCommand(),
+            searchCommand(),
+            listCommand(),
+            rmCommand(),
+            reindexCommand(),
+            serveCommand(),
+        },
+    }
+}
+
+func setup(c *cli.Context) error {
+    if verbose {
+        log.SetPatterns("*")
+    }
+
+    var err error
+    cfg, err = refman.LoadConfig(configFile)
+    if err != nil {
+        return err
+    }
+
+    logger.Printf("Using working directory: %s\n", cfg.WorkDir)
+    return os.MkdirAll(cfg.WorkDir, 0777)
+}
+
+func openIndex() (bleve.Index, error) {
+    logger.Printf("Opening index file: %s\n", cfg.IndexPath())
+    return refman.OpenIndex(cfg.IndexPath(), cfg.ManifestPath())
+}
+
+func openManifest() (*refman.Manifest, error) {
+    return refman.LoadManifest(cfg.ManifestPath())
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+    "fmt"
+
+    "github.com/urfave/cli/v2"
+)
+
+func listCommand() *cli.Command {
+    return &cli.Command{
+        Name:  "list",
+        Usage: "list every entry tracked in the index",
+        Action: func(c *cli.Context) error {
+            manifest, err := openManifest()
+            if err != nil {
+                return err
+            }
+
+            for id, entry := range manifest.Entries {
+                fmt.Printf("%s\t%s\n", id, entry.PDFPath)
+            }
+            return nil
+        },
+    }
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+    "github.com/urfave/cli/v2"
+
+    "github.com/sug0/refman/internal/refman"
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+var reindexLogger = log.Component("cmd.reindex")
+
+func reindexCommand() *cli.Command {
+    return &cli.Command{
+        Name:  "reindex",
+        Usage: "rebuild the index from the PDFs and BibTeX files recorded in the manifest",
+        Action: func(c *cli.Context) error {
+            manifest, err := openManifest()
+            if err != nil {
+                return err
+            }
+
+            index, err := openIndex()
+            if err != nil {
+                return err
+            }
+            defer index.Close()
+
+            for id, entry := range manifest.Entries {
+                reindexLogger.Printf("Reindexing entry: %s\n", id)
+                doc, err := refman.ParseDocument(entry.PDFPath, entry.BibtexPath)
+                if err != nil {
+                    return err
+                }
+                if err := index.Index(id, doc); err != nil {
+                    return err
+                }
+            }
+            return nil
+        },
+    }
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+    "errors"
+
+    "github.com/urfave/cli/v2"
+
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+var rmLogger = log.Component("cmd.rm")
+
+func rmCommand() *cli.Command {
+    return &cli.Command{
+        Name:      "rm",
+        Usage:     "remove an entry from the index",
+        ArgsUsage: "<id>",
+        Action: func(c *cli.Context) error {
+            if c.NArg() != 1 {
+                return errors.New("rm: expected exactly one entry id")
+            }
+            id := c.Args().First()
+
+            index, err := openIndex()
+            if err != nil {
+                return err
+            }
+            defer index.Close()
+
+            if err := index.Delete(id); err != nil {
+                return err
+            }
+
+            manifest, err := openManifest()
+            if err != nil {
+                return err
+            }
+            manifest.Delete(id)
+            if err := manifest.Save(); err != nil {
+                return err
+            }
+
+            rmLogger.Printf("Removed entry: %s\n", id)
+            return nil
+        },
+    }
+}
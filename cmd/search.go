@@ -0,0 +1,57 @@
+package cmd
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/blevesearch/bleve"
+    "github.com/blevesearch/bleve/search/highlight/highlighter/ansi"
+    "github.com/urfave/cli/v2"
+
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+var searchLogger = log.Component("cmd.search")
+
+func searchCommand() *cli.Command {
+    var highlight bool
+
+    return &cli.Command{
+        Name:      "search",
+        Usage:     "search the index",
+        ArgsUsage: "<query>",
+        Flags: []cli.Flag{
+            &cli.BoolFlag{
+                Name:        "highlight",
+                Usage:       "Highlight matches in the terminal.",
+                Destination: &highlight,
+            },
+        },
+        Action: func(c *cli.Context) error {
+            if c.NArg() == 0 {
+                searchLogger.Println("No query given, leaving.")
+                return nil
+            }
+
+            index, err := openIndex()
+            if err != nil {
+                return err
+            }
+            defer index.Close()
+
+            s := strings.ReplaceAll(strings.Join(c.Args().Slice(), " "), "~", "-")
+            queryString := bleve.NewQueryStringQuery(s)
+            searchRequest := bleve.NewSearchRequest(queryString)
+            if highlight {
+                searchRequest.Highlight = bleve.NewHighlightWithStyle(ansi.Name)
+            }
+
+            result, err := index.Search(searchRequest)
+            if err != nil {
+                return err
+            }
+            fmt.Println(result)
+            return nil
+        },
+    }
+}
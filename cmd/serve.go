@@ -0,0 +1,48 @@
+package cmd
+
+import (
+    "net/http"
+    "os"
+    "path/filepath"
+
+    "github.com/urfave/cli/v2"
+
+    "github.com/sug0/refman/internal/refman/httpapi"
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+var serveLogger = log.Component("cmd.serve")
+
+func serveCommand() *cli.Command {
+    return &cli.Command{
+        Name:  "serve",
+        Usage: "expose the index over HTTP/JSON",
+        Action: func(c *cli.Context) error {
+            index, err := openIndex()
+            if err != nil {
+                return err
+            }
+            defer index.Close()
+
+            manifest, err := openManifest()
+            if err != nil {
+                return err
+            }
+
+            docsDir := filepath.Join(cfg.WorkDir, "docs")
+            if err := os.MkdirAll(docsDir, 0777); err != nil {
+                return err
+            }
+
+            srv := httpapi.NewServer(index, manifest, docsDir)
+            httpServer := &http.Server{
+                Addr:              cfg.Server.HTTP.Addr,
+                Handler:           srv.Handler(),
+                ReadHeaderTimeout: cfg.Server.HTTP.ReadHeaderTimeout,
+            }
+
+            serveLogger.Printf("Listening on %s\n", cfg.Server.HTTP.Addr)
+            return httpServer.ListenAndServe()
+        },
+    }
+}
@@ -0,0 +1,65 @@
+package refman
+
+import (
+    "bytes"
+
+    "github.com/blevesearch/bleve/analysis"
+    "github.com/blevesearch/bleve/registry"
+)
+
+// authorAnalyzerName is referenced from NewMapping's "author" field
+// mapping.
+const authorAnalyzerName = "bibtexAuthor"
+
+var authorSeparator = []byte(" and ")
+
+func init() {
+    registry.RegisterTokenizer(authorAnalyzerName, func(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+        return authorTokenizer{}, nil
+    })
+    registry.RegisterAnalyzer(authorAnalyzerName, func(config map[string]interface{}, cache *registry.Cache) (*analysis.Analyzer, error) {
+        tokenizer, err := cache.TokenizerNamed(authorAnalyzerName)
+        if err != nil {
+            return nil, err
+        }
+        return &analysis.Analyzer{Tokenizer: tokenizer}, nil
+    })
+}
+
+// authorTokenizer splits a BibTeX "author" field on its " and "
+// separator -- the way BibTeX itself delimits multiple authors --
+// emitting each author's full name as a single keyword token rather
+// than tokenizing it further.
+type authorTokenizer struct{}
+
+func (authorTokenizer) Tokenize(input []byte) analysis.TokenStream {
+    var stream analysis.TokenStream
+
+    start, pos := 0, 1
+    for {
+        end := len(input)
+        idx := bytes.Index(input[start:], authorSeparator)
+        if idx >= 0 {
+            end = start + idx
+        }
+
+        if term := bytes.TrimSpace(input[start:end]); len(term) > 0 {
+            stream = append(stream, &analysis.Token{
+                Term:     term,
+                Start:    start,
+                End:      end,
+                Position: pos,
+                Type:     analysis.Single,
+                KeyWord:  true,
+            })
+            pos++
+        }
+
+        if idx < 0 {
+            break
+        }
+        start = end + len(authorSeparator)
+    }
+
+    return stream
+}
@@ -0,0 +1,25 @@
+package refman
+
+import (
+    "bufio"
+    "os"
+
+    "github.com/nickng/bibtex"
+
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+var bibtexLog = log.Component("bibtex.parse")
+
+// ParseBibTex opens and parses the BibTeX file at path.
+func ParseBibTex(path string) (*bibtex.BibTex, error) {
+    bibtexLog.Printf("Parsing BibTeX file: %s\n", path)
+
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    return bibtex.Parse(bufio.NewReader(f))
+}
@@ -0,0 +1,95 @@
+package refman
+
+import (
+    "os"
+    "path/filepath"
+    "runtime"
+    "time"
+
+    "gopkg.in/yaml.v2"
+)
+
+// Config holds every setting refman needs at startup. It is built up
+// in increasing order of precedence: built-in defaults, then the
+// config file (if any), then environment variables.
+type Config struct {
+    WorkDir string `yaml:"workDir"`
+    Server  struct {
+        HTTP struct {
+            Addr              string        `yaml:"addr"`
+            ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+        } `yaml:"http"`
+    } `yaml:"server"`
+}
+
+// IndexPath returns the path of the Bleve index under the config's
+// working directory.
+func (c *Config) IndexPath() string {
+    return filepath.Join(c.WorkDir, "index.bleve")
+}
+
+// ManifestPath returns the path of the manifest file under the
+// config's working directory.
+func (c *Config) ManifestPath() string {
+    return filepath.Join(c.WorkDir, "manifest.json")
+}
+
+// DefaultConfig returns a Config populated with refman's built-in
+// defaults.
+func DefaultConfig() *Config {
+    c := &Config{WorkDir: defaultWorkDir()}
+    c.Server.HTTP.Addr = ":8080"
+    c.Server.HTTP.ReadHeaderTimeout = 5 * time.Second
+    return c
+}
+
+// LoadConfig builds a Config by starting from DefaultConfig, then
+// overlaying the YAML file at path (if it exists), then overlaying
+// any REFMAN_* environment variables that are set.
+func LoadConfig(path string) (*Config, error) {
+    cfg := DefaultConfig()
+
+    if path != "" {
+        f, err := os.Open(path)
+        switch {
+        case err == nil:
+            defer f.Close()
+            if err := yaml.NewDecoder(f).Decode(cfg); err != nil {
+                return nil, err
+            }
+        case os.IsNotExist(err):
+            // nothing to overlay
+        default:
+            return nil, err
+        }
+    }
+
+    if envDir := os.Getenv("REFMAN_WORKDIR"); envDir != "" {
+        cfg.WorkDir = envDir
+    }
+    if envAddr := os.Getenv("REFMAN_HTTP_ADDR"); envAddr != "" {
+        cfg.Server.HTTP.Addr = envAddr
+    }
+    if envTimeout := os.Getenv("REFMAN_HTTP_READ_HEADER_TIMEOUT"); envTimeout != "" {
+        d, err := time.ParseDuration(envTimeout)
+        if err != nil {
+            return nil, err
+        }
+        cfg.Server.HTTP.ReadHeaderTimeout = d
+    }
+
+    return cfg, nil
+}
+
+func defaultWorkDir() string {
+    switch runtime.GOOS {
+    case "windows":
+        return filepath.Join(os.Getenv("APPDATA"), "refman")
+    default:
+        home, err := os.UserHomeDir()
+        if err != nil {
+            panic(err)
+        }
+        return filepath.Join(home, ".local/share/refman")
+    }
+}
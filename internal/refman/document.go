@@ -0,0 +1,73 @@
+package refman
+
+import (
+    "strconv"
+    "strings"
+
+    "github.com/nickng/bibtex"
+)
+
+// documentType names the document mapping Document is indexed
+// under; see NewMapping.
+const documentType = "document"
+
+// Document is the unit of content stored in the search index: the
+// parsed BibTeX reference and extracted PDF text, plus the flattened
+// BibTeX fields NewMapping indexes individually so that queries like
+// "author:knuth year:1974" work.
+type Document struct {
+    Ref *bibtex.BibTex `json:"ref,omitempty"`
+    Txt string         `json:"txt"`
+    Path string        `json:"path,omitempty"`
+
+    EntryType string  `json:"entryType,omitempty"`
+    CiteKey   string  `json:"citeKey,omitempty"`
+    Title     string  `json:"title,omitempty"`
+    Author    string  `json:"author,omitempty"`
+    Year      float64 `json:"year,omitempty"`
+    Journal   string  `json:"journal,omitempty"`
+    Doi       string  `json:"doi,omitempty"`
+    Abstract  string  `json:"abstract,omitempty"`
+}
+
+// Type implements bleve.Classifier, giving the index mapping a
+// stable document type name to key Document's mapping on.
+func (d *Document) Type() string {
+    return documentType
+}
+
+// populateFromRef copies the fields NewMapping cares about out of
+// Ref's first BibTeX entry. A bibtex file handed to refman is
+// expected to describe the one reference the PDF corresponds to, so
+// only Entries[0] is considered.
+func (d *Document) populateFromRef() {
+    if d.Ref == nil || len(d.Ref.Entries) == 0 {
+        return
+    }
+
+    entry := d.Ref.Entries[0]
+    d.EntryType = entry.Type
+    d.CiteKey = entry.CiteName
+    d.Title = bibField(entry, "title")
+    d.Author = bibField(entry, "author")
+    d.Journal = bibField(entry, "journal")
+    d.Doi = bibField(entry, "doi")
+    d.Abstract = bibField(entry, "abstract")
+
+    if year := bibField(entry, "year"); year != "" {
+        if y, err := strconv.ParseFloat(year, 64); err == nil {
+            d.Year = y
+        }
+    }
+}
+
+// bibField looks up a BibTeX field by name, case-insensitively,
+// returning its displayed string value.
+func bibField(entry *bibtex.BibEntry, name string) string {
+    for k, v := range entry.Fields {
+        if strings.EqualFold(k, name) {
+            return v.String()
+        }
+    }
+    return ""
+}
@@ -0,0 +1,110 @@
+package httpapi
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/blevesearch/bleve/mapping"
+    "github.com/blevesearch/bleve/search"
+    "github.com/nickng/bibtex"
+)
+
+// Hit is the JSON representation of a single search result: the
+// Bleve document ID and score, the parsed BibTeX reference it
+// points at, and a per-field breakdown of how the query matched.
+type Hit struct {
+    ID      string           `json:"id"`
+    Score   float64          `json:"score"`
+    Ref     *bibtex.BibTex   `json:"ref,omitempty"`
+    Matches map[string]Match `json:"matches"`
+}
+
+// Match describes how well a single field matched the query.
+// MatchLevel reflects what fraction of the field's tokens matched;
+// FullyHighlighted instead reflects whether Bleve's returned
+// fragment happens to cover the field's whole value, which can
+// differ from MatchLevel for short fields fragmented in full.
+type Match struct {
+    Value            string   `json:"value"`
+    MatchLevel       string   `json:"matchLevel"`
+    FullyHighlighted bool     `json:"fullyHighlighted"`
+    MatchedWords     []string `json:"matchedWords,omitempty"`
+}
+
+// newHit builds a Hit DTO out of a raw Bleve document match. im is
+// the mapping the index was built with, needed to tokenize field
+// values the same way the index did when deriving match levels.
+func newHit(dm *search.DocumentMatch, ref *bibtex.BibTex, im mapping.IndexMapping) Hit {
+    hit := Hit{
+        ID:      dm.ID,
+        Score:   dm.Score,
+        Ref:     ref,
+        Matches: make(map[string]Match, len(dm.Fields)),
+    }
+
+    for field, value := range dm.Fields {
+        hit.Matches[field] = newMatch(dm, field, fmt.Sprint(value), im)
+    }
+
+    return hit
+}
+
+// newMatch derives the match level of field from how much of its
+// text the query actually matched: no term locations means "none",
+// every token in the field having a matched location means "full",
+// and anything in between means "partial". This is based on term
+// coverage rather than the highlighted fragment, since Bleve's
+// default fragmenter returns fragments up to 200 bytes long and so
+// echoes back the entire value of most flattened BibTeX fields
+// regardless of how much of it actually matched. Coverage is
+// measured against the field's own analyzer output rather than a
+// naive whitespace split, since fields like "author" are tokenized
+// into one token per author rather than one per word.
+func newMatch(dm *search.DocumentMatch, field, value string, im mapping.IndexMapping) Match {
+    terms := dm.Locations[field]
+    if len(terms) == 0 {
+        return Match{Value: value, MatchLevel: "none"}
+    }
+
+    words := make([]string, 0, len(terms))
+    matchedPositions := make(map[uint64]struct{})
+    for term, locs := range terms {
+        words = append(words, term)
+        for _, loc := range locs {
+            matchedPositions[loc.Pos] = struct{}{}
+        }
+    }
+    sort.Strings(words)
+
+    level := "partial"
+    if totalWords := fieldTokenCount(im, field, value); totalWords > 0 && len(matchedPositions) >= totalWords {
+        level = "full"
+    }
+
+    fullyHighlighted := stripHighlight(strings.Join(dm.Fragments[field], "")) == value
+
+    return Match{
+        Value:            value,
+        MatchLevel:       level,
+        FullyHighlighted: fullyHighlighted,
+        MatchedWords:     words,
+    }
+}
+
+// fieldTokenCount returns how many tokens the analyzer mapped to
+// field would produce from value. It falls back to a whitespace
+// split if im has no analyzer registered for field.
+func fieldTokenCount(im mapping.IndexMapping, field, value string) int {
+    analyzer := im.AnalyzerNamed(im.AnalyzerNameForPath(field))
+    if analyzer == nil {
+        return len(strings.Fields(value))
+    }
+    return len(analyzer.Analyze([]byte(value)))
+}
+
+func stripHighlight(fragment string) string {
+    fragment = strings.ReplaceAll(fragment, "<mark>", "")
+    fragment = strings.ReplaceAll(fragment, "</mark>", "")
+    return fragment
+}
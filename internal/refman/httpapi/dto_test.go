@@ -0,0 +1,76 @@
+package httpapi
+
+import (
+    "testing"
+
+    "github.com/blevesearch/bleve/search"
+
+    "github.com/sug0/refman/internal/refman"
+)
+
+// TestNewMatchLevelTracksTermCoverage guards against matchLevel being
+// derived from whether the highlighted fragment equals the whole
+// field value: Bleve's default fragment size (200 bytes) means a
+// short field is echoed back in full on any match, so that check
+// alone can't distinguish a partial match from a complete one.
+func TestNewMatchLevelTracksTermCoverage(t *testing.T) {
+    im := refman.NewMapping()
+    value := "Deep Residual Learning for Image Recognition"
+
+    dm := &search.DocumentMatch{
+        Locations: search.FieldTermLocationMap{
+            "title": search.TermLocationMap{
+                "deep": search.Locations{{Pos: 1}},
+            },
+        },
+        Fragments: search.FieldFragmentMap{
+            "title": []string{"<mark>Deep</mark> Residual Learning for Image Recognition"},
+        },
+    }
+
+    match := newMatch(dm, "title", value, im)
+    if match.MatchLevel != "partial" {
+        t.Errorf("MatchLevel = %q, want %q (only 1 of 6 tokens matched)", match.MatchLevel, "partial")
+    }
+
+    dm.Locations["title"] = search.TermLocationMap{
+        "deep":        search.Locations{{Pos: 1}},
+        "residual":    search.Locations{{Pos: 2}},
+        "learning":    search.Locations{{Pos: 3}},
+        "for":         search.Locations{{Pos: 4}},
+        "image":       search.Locations{{Pos: 5}},
+        "recognition": search.Locations{{Pos: 6}},
+    }
+
+    match = newMatch(dm, "title", value, im)
+    if match.MatchLevel != "full" {
+        t.Errorf("MatchLevel = %q, want %q (all 6 tokens matched)", match.MatchLevel, "full")
+    }
+}
+
+// TestNewMatchLevelUsesFieldAnalyzer guards against match level being
+// computed from a naive whitespace split: "author" is tokenized into
+// one token per author (see authorTokenizer), not one per word, so a
+// field with two fully-matched authors but six whitespace-separated
+// words must still report "full".
+func TestNewMatchLevelUsesFieldAnalyzer(t *testing.T) {
+    im := refman.NewMapping()
+    value := "Donald E. Knuth and Leslie Lamport"
+
+    dm := &search.DocumentMatch{
+        Locations: search.FieldTermLocationMap{
+            "author": search.TermLocationMap{
+                "donald e. knuth": search.Locations{{Pos: 1}},
+                "leslie lamport":  search.Locations{{Pos: 2}},
+            },
+        },
+        Fragments: search.FieldFragmentMap{
+            "author": []string{value},
+        },
+    }
+
+    match := newMatch(dm, "author", value, im)
+    if match.MatchLevel != "full" {
+        t.Errorf("MatchLevel = %q, want %q (both authors matched)", match.MatchLevel, "full")
+    }
+}
@@ -0,0 +1,226 @@
+// Package httpapi exposes a refman index over HTTP/JSON, so editors
+// and web UIs can query it without shelling out to the refman CLI.
+package httpapi
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/blevesearch/bleve"
+    "github.com/nickng/bibtex"
+
+    "github.com/sug0/refman/internal/refman"
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+var (
+    searchLog = log.Component("http.search")
+    docLog    = log.Component("http.doc")
+)
+
+// Server answers HTTP requests against an open index and its
+// manifest. It does not own the lifetime of either: the caller is
+// responsible for closing the index once the server stops.
+type Server struct {
+    Index    bleve.Index
+    Manifest *refman.Manifest
+    DocsDir  string
+}
+
+// NewServer builds a Server that persists uploaded documents under
+// docsDir.
+func NewServer(index bleve.Index, manifest *refman.Manifest, docsDir string) *Server {
+    return &Server{Index: index, Manifest: manifest, DocsDir: docsDir}
+}
+
+// Handler returns the HTTP handler that serves the search API.
+func (s *Server) Handler() http.Handler {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/search", s.handleSearch)
+    mux.HandleFunc("/doc", s.handleDoc)
+    return mux
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query().Get("q")
+    if q == "" {
+        http.Error(w, "missing q parameter", http.StatusBadRequest)
+        return
+    }
+
+    queryString := bleve.NewQueryStringQuery(strings.ReplaceAll(q, "~", "-"))
+    searchRequest := bleve.NewSearchRequest(queryString)
+    searchRequest.Fields = []string{"*"}
+    searchRequest.Highlight = bleve.NewHighlight()
+
+    result, err := s.Index.Search(searchRequest)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    im := s.Index.Mapping()
+    hits := make([]Hit, 0, len(result.Hits))
+    for _, dm := range result.Hits {
+        hits = append(hits, newHit(dm, s.refFor(dm.ID), im))
+    }
+    s.writeJSON(w, hits)
+}
+
+// handleDoc dispatches /doc requests by method. Document ids are
+// absolute filesystem paths (see ParseDocument/saveUpload), so they
+// are carried as a "id" query parameter rather than a URL path
+// segment: a leading "/" in a path segment collides with
+// net/http.ServeMux's path-cleaning, which collapses the resulting
+// double slash and 301-redirects the request before it ever reaches
+// this handler.
+func (s *Server) handleDoc(w http.ResponseWriter, r *http.Request) {
+    switch r.Method {
+    case http.MethodPost:
+        s.handleDocCreate(w, r)
+    case http.MethodGet:
+        s.handleDocByID(w, r, s.handleDocGet)
+    case http.MethodDelete:
+        s.handleDocByID(w, r, s.handleDocDelete)
+    default:
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+    }
+}
+
+// handleDocByID extracts the "id" query parameter and hands it to fn,
+// rejecting the request if it is missing.
+func (s *Server) handleDocByID(w http.ResponseWriter, r *http.Request, fn func(http.ResponseWriter, string)) {
+    id := r.URL.Query().Get("id")
+    if id == "" {
+        http.Error(w, "missing id parameter", http.StatusBadRequest)
+        return
+    }
+    fn(w, id)
+}
+
+func (s *Server) handleDocGet(w http.ResponseWriter, id string) {
+    entry, ok := s.Manifest.Get(id)
+    if !ok {
+        http.Error(w, "no such document", http.StatusNotFound)
+        return
+    }
+
+    doc, err := refman.ParseDocument(entry.PDFPath, entry.BibtexPath)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    s.writeJSON(w, doc)
+}
+
+func (s *Server) handleDocCreate(w http.ResponseWriter, r *http.Request) {
+    if err := r.ParseMultipartForm(32 << 20); err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    pdfPath, err := s.saveUpload(r, "pdf")
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    bibtexPath, err := s.saveUpload(r, "bibtex")
+    if err != nil && err != http.ErrMissingFile {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    docLog.Printf("Parsing uploaded PDF file: %s\n", pdfPath)
+    doc, err := refman.ParseDocument(pdfPath, bibtexPath)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    if err := s.Index.Index(doc.Path, doc); err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    s.Manifest.Put(doc.Path, refman.Entry{PDFPath: doc.Path, BibtexPath: bibtexPath})
+    if err := s.Manifest.Save(); err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusCreated)
+    s.writeJSON(w, map[string]string{"id": doc.Path})
+}
+
+func (s *Server) handleDocDelete(w http.ResponseWriter, id string) {
+    if err := s.Index.Delete(id); err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    s.Manifest.Delete(id)
+    if err := s.Manifest.Save(); err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// saveUpload copies the named multipart file, if present, into the
+// server's docs directory and returns the path it was saved to. Only
+// the base name of the client-supplied filename is trusted, so a
+// crafted filename like "../../etc/cron.d/x" can't write outside
+// DocsDir.
+func (s *Server) saveUpload(r *http.Request, name string) (string, error) {
+    file, header, err := r.FormFile(name)
+    if err != nil {
+        return "", err
+    }
+    defer file.Close()
+
+    base := filepath.Base(header.Filename)
+    if base == "" || base == "." || base == ".." {
+        return "", fmt.Errorf("invalid upload filename: %q", header.Filename)
+    }
+
+    dst := filepath.Join(s.DocsDir, base)
+    out, err := os.Create(dst)
+    if err != nil {
+        return "", err
+    }
+    defer out.Close()
+
+    if _, err := out.ReadFrom(file); err != nil {
+        return "", err
+    }
+    return dst, nil
+}
+
+// refFor parses the BibTeX reference for a stored document, if it
+// has one, without paying the cost of re-extracting its PDF text.
+func (s *Server) refFor(id string) *bibtex.BibTex {
+    entry, ok := s.Manifest.Get(id)
+    if !ok || entry.BibtexPath == "" {
+        return nil
+    }
+
+    ref, err := refman.ParseBibTex(entry.BibtexPath)
+    if err != nil {
+        searchLog.Printf("Failed to parse BibTeX file %s: %s\n", entry.BibtexPath, err)
+        return nil
+    }
+    return ref
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, v interface{}) {
+    w.Header().Set("Content-Type", "application/json")
+    if err := json.NewEncoder(w).Encode(v); err != nil {
+        docLog.Printf("Failed to write JSON response: %s\n", err)
+    }
+}
@@ -0,0 +1,112 @@
+package httpapi
+
+import (
+    "encoding/base64"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/blevesearch/bleve"
+
+    "github.com/sug0/refman/internal/refman"
+)
+
+// emptyPDFBase64 is pdfcpu's "empty.pdf" test fixture: a minimal,
+// valid, single-page PDF with no text content, used here so the
+// server's doc routes can be exercised without shelling out to a
+// real PDF file.
+const emptyPDFBase64 = `JVBERi0xLjcKJeLjz9MKMSAwIG9iago8PC9QYWdlcyAyIDAgUi9UeXBlL0NhdGFsb2c+PgplbmRvYmoKNCAwIG9iago8PC9GaWx0
+ZXIvRmxhdGVEZWNvZGUvTGVuZ3RoIDExPj4Kc3RyZWFtCnicAQAA//8AAAABZW5kc3RyZWFtCmVuZG9iagoyMyAwIG9iago8PC9G
+aWx0ZXIvRmxhdGVEZWNvZGUvRmlyc3QgMTQvTGVuZ3RoIDE2Ni9OIDMvVHlwZS9PYmpTdG0+PgpzdHJlYW0KeJxczkHKwjAQBeCr
+zAn+Sdr+ugmzaEEEEUp1V7qI7SAFSaSZit5epi6UZhPem2/x/sFADtsCMrCZdQ6rGISDJCjAQIO1nzgIZEtoOMV56jk5h7sYRD8L
+ud6IiPD8ujPW/spEzmHpE6vCPd8eLGPv8TRfRI1C++EqFl7FOQhYPIxDanVW0+GRh9GX8dmaP/PzYBULsyo2q6L7TktE7wAAAP//
+bYpDg2VuZHN0cmVhbQplbmRvYmoKNiAwIG9iago8PC9DcmVhdGlvbkRhdGUoRDoyMDE5MDcwNDEwMjcyOCswMicwMCcpL01vZERh
+dGUoRDoyMDE5MDcwNDEwMjcyOCswMicwMCcpL1Byb2R1Y2VyKHBkZmNwdSB2MC4xLjI1KT4+CmVuZG9iagoyMiAwIG9iago8PC9G
+aWx0ZXIvRmxhdGVEZWNvZGUvSURbPDEyNjNDMjQ4RDcyOUI5MTNGQzM5MkYyNjQ2MTk1NDJBPiA8MGNiOTUzNjAyNzk4NWQ1NTQ5
+YzNlY2ZlNmE5MjM5ZTc+XS9JbmRleFswIDIyIDIzIDFdL0luZm8gNiAwIFIvTGVuZ3RoIDcyL1Jvb3QgMSAwIFIvU2l6ZSAyNC9U
+eXBlL1hSZWYvV1sxIDIgMl0+PgpzdHJlYW0KeJwkzEkKgDAUBNH62TgbR7yMFxS8c6RMLx70puAsJciQuEgSwV0vES//AhpppZNe
+BhllklmyLLLKJrsclh/4AgAA//9tzQSTZW5kc3RyZWFtCmVuZG9iagoKc3RhcnR4cmVmCjUxMgolJUVPRg==`
+
+// newTestServer builds a Server backed by a throwaway in-memory
+// index and manifest, plus a temp docs directory holding one PDF
+// whose id (an absolute path, as every real document's is) is
+// returned alongside it.
+func newTestServer(t *testing.T) (*Server, string) {
+    t.Helper()
+
+    dir := t.TempDir()
+
+    raw, err := base64.StdEncoding.DecodeString(emptyPDFBase64)
+    if err != nil {
+        t.Fatalf("decode fixture: %s", err)
+    }
+    pdfPath := filepath.Join(dir, "doc.pdf")
+    if err := os.WriteFile(pdfPath, raw, 0o644); err != nil {
+        t.Fatalf("write fixture: %s", err)
+    }
+    absPath, err := filepath.Abs(pdfPath)
+    if err != nil {
+        t.Fatalf("Abs: %s", err)
+    }
+
+    index, err := bleve.NewMemOnly(refman.NewMapping())
+    if err != nil {
+        t.Fatalf("NewMemOnly: %s", err)
+    }
+    t.Cleanup(func() { index.Close() })
+
+    doc, err := refman.ParseDocument(pdfPath, "")
+    if err != nil {
+        t.Fatalf("ParseDocument: %s", err)
+    }
+    if err := index.Index(doc.Path, doc); err != nil {
+        t.Fatalf("Index: %s", err)
+    }
+
+    manifest, err := refman.LoadManifest(filepath.Join(dir, "manifest.json"))
+    if err != nil {
+        t.Fatalf("LoadManifest: %s", err)
+    }
+    manifest.Put(absPath, refman.Entry{PDFPath: absPath})
+
+    return NewServer(index, manifest, dir), absPath
+}
+
+// TestDocRoutesRoundTripAbsolutePathID guards against GET/DELETE
+// /doc routing being unreachable for real document ids: every id is
+// an absolute path starting with "/", which used to be routed as a
+// URL path segment and fell victim to net/http.ServeMux's
+// path-cleaning redirect on the resulting double slash.
+func TestDocRoutesRoundTripAbsolutePathID(t *testing.T) {
+    server, id := newTestServer(t)
+    handler := server.Handler()
+
+    getReq := httptest.NewRequest("GET", "/doc?id="+id, nil)
+    getRec := httptest.NewRecorder()
+    handler.ServeHTTP(getRec, getReq)
+
+    if getRec.Code != 200 {
+        t.Fatalf("GET /doc?id=%s: status = %d, body = %s", id, getRec.Code, getRec.Body.String())
+    }
+
+    delReq := httptest.NewRequest("DELETE", "/doc?id="+id, nil)
+    delRec := httptest.NewRecorder()
+    handler.ServeHTTP(delRec, delReq)
+
+    if delRec.Code != 204 {
+        t.Fatalf("DELETE /doc?id=%s: status = %d, body = %s", id, delRec.Code, delRec.Body.String())
+    }
+
+    if _, ok := server.Manifest.Get(id); ok {
+        t.Error("manifest entry still present after DELETE")
+    }
+
+    getAfterDeleteReq := httptest.NewRequest("GET", "/doc?id="+id, nil)
+    getAfterDeleteRec := httptest.NewRecorder()
+    handler.ServeHTTP(getAfterDeleteRec, getAfterDeleteReq)
+
+    if getAfterDeleteRec.Code != 404 {
+        t.Errorf("GET /doc?id=%s after delete: status = %d, want 404", id, getAfterDeleteRec.Code)
+    }
+}
@@ -0,0 +1,166 @@
+package refman
+
+import (
+    "os"
+    "strconv"
+
+    "github.com/blevesearch/bleve"
+    "github.com/blevesearch/bleve/mapping"
+
+    // Registers the "keyword" analyzer used by entryType/citeKey/doi/path.
+    _ "github.com/blevesearch/bleve/analysis/analyzer/keyword"
+
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+var (
+    indexLog        = log.Component("index.open")
+    indexMigrateLog = log.Component("index.migrate")
+)
+
+// mappingVersionKey stores the mapping version an index was built
+// with, via Bleve's internal key/value store.
+const mappingVersionKey = "_refman_mapping_version"
+
+// MappingVersion must be bumped whenever NewMapping's field shape
+// changes, so that OpenIndex knows to migrate indexes built under an
+// older mapping.
+const MappingVersion = 1
+
+// NewMapping builds the index mapping used for every refman index.
+// BibTeX fields are flattened into first-class, per-field-analyzed
+// fields instead of being indexed opaquely, so queries like
+// "author:knuth year:1974" work as expected.
+func NewMapping() *mapping.IndexMappingImpl {
+    im := bleve.NewIndexMapping()
+
+    keyword := bleve.NewTextFieldMapping()
+    keyword.Analyzer = "keyword"
+
+    text := bleve.NewTextFieldMapping()
+
+    author := bleve.NewTextFieldMapping()
+    author.Analyzer = authorAnalyzerName
+
+    year := bleve.NewNumericFieldMapping()
+
+    doc := bleve.NewDocumentMapping()
+    doc.AddFieldMappingsAt("entryType", keyword)
+    doc.AddFieldMappingsAt("citeKey", keyword)
+    doc.AddFieldMappingsAt("title", text)
+    doc.AddFieldMappingsAt("author", author)
+    doc.AddFieldMappingsAt("year", year)
+    doc.AddFieldMappingsAt("journal", text)
+    doc.AddFieldMappingsAt("doi", keyword)
+    doc.AddFieldMappingsAt("abstract", text)
+    doc.AddFieldMappingsAt("txt", text)
+    doc.AddFieldMappingsAt("path", keyword)
+
+    // Ref is a raw *bibtex.BibTex: its fields are BibString
+    // interfaces that reflection-based mapping cannot index
+    // meaningfully, so it is kept out of the index entirely in
+    // favour of the flattened fields above. Bleve resolves this path
+    // from Document's "ref" json tag, not its "Ref" field name, so
+    // the sub-mapping must be keyed on the lowercase tag.
+    doc.AddSubDocumentMapping("ref", bleve.NewDocumentDisabledMapping())
+
+    im.AddDocumentMapping(documentType, doc)
+    return im
+}
+
+// OpenIndex opens the Bleve index at path, creating it with
+// NewMapping if it does not already exist. An index built under an
+// older mapping version is migrated in place before being returned.
+func OpenIndex(indexPath, manifestPath string) (bleve.Index, error) {
+    indexLog.Printf("Opening index file: %s\n", indexPath)
+
+    index, err := bleve.Open(indexPath)
+    if err != nil {
+        indexLog.Println("Open failed, creating index.")
+        index, err = bleve.New(indexPath, NewMapping())
+        if err != nil {
+            return nil, err
+        }
+        return index, setMappingVersion(index)
+    }
+
+    version, err := mappingVersionOf(index)
+    if err != nil {
+        return nil, err
+    }
+    if version == MappingVersion {
+        return index, nil
+    }
+
+    indexLog.Printf("Index at %s is on mapping version %d, migrating to %d.\n", indexPath, version, MappingVersion)
+    return migrateIndex(index, indexPath, manifestPath)
+}
+
+func mappingVersionOf(index bleve.Index) (int, error) {
+    raw, err := index.GetInternal([]byte(mappingVersionKey))
+    if err != nil {
+        return 0, err
+    }
+    if raw == nil {
+        return 0, nil
+    }
+    return strconv.Atoi(string(raw))
+}
+
+func setMappingVersion(index bleve.Index) error {
+    return index.SetInternal([]byte(mappingVersionKey), []byte(strconv.Itoa(MappingVersion)))
+}
+
+// migrateIndex rebuilds the index at indexPath under NewMapping,
+// reindexing every PDF/BibTeX pair recorded in the manifest at
+// manifestPath from disk, then atomically swaps it in place of the
+// outdated index.
+func migrateIndex(oldIndex bleve.Index, indexPath, manifestPath string) (bleve.Index, error) {
+    manifest, err := LoadManifest(manifestPath)
+    if err != nil {
+        return nil, err
+    }
+
+    tmpPath := indexPath + ".migrating"
+    if err := os.RemoveAll(tmpPath); err != nil {
+        return nil, err
+    }
+
+    newIndex, err := bleve.New(tmpPath, NewMapping())
+    if err != nil {
+        return nil, err
+    }
+
+    for id, entry := range manifest.Entries {
+        indexMigrateLog.Printf("Reindexing entry: %s\n", id)
+        doc, err := ParseDocument(entry.PDFPath, entry.BibtexPath)
+        if err != nil {
+            newIndex.Close()
+            return nil, err
+        }
+        if err := newIndex.Index(id, doc); err != nil {
+            newIndex.Close()
+            return nil, err
+        }
+    }
+
+    if err := setMappingVersion(newIndex); err != nil {
+        newIndex.Close()
+        return nil, err
+    }
+    if err := newIndex.Close(); err != nil {
+        return nil, err
+    }
+    if err := oldIndex.Close(); err != nil {
+        return nil, err
+    }
+
+    if err := os.RemoveAll(indexPath); err != nil {
+        return nil, err
+    }
+    if err := os.Rename(tmpPath, indexPath); err != nil {
+        return nil, err
+    }
+
+    return bleve.Open(indexPath)
+}
@@ -0,0 +1,46 @@
+package refman
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/blevesearch/bleve"
+    "github.com/nickng/bibtex"
+)
+
+// TestNewMappingDisablesRef guards against the sub-document mapping
+// being keyed on the wrong path: Ref must not be dynamically indexed
+// under any "ref.*" field, since its BibString fields aren't
+// meaningfully searchable and it duplicates the flattened fields.
+func TestNewMappingDisablesRef(t *testing.T) {
+    idx, err := bleve.NewMemOnly(NewMapping())
+    if err != nil {
+        t.Fatalf("NewMemOnly: %s", err)
+    }
+    defer idx.Close()
+
+    entry := bibtex.NewBibEntry("article", "knuth1974")
+    entry.AddField("title", bibtex.NewBibConst("Structured Programming"))
+    entry.AddField("author", bibtex.NewBibConst("Knuth, Donald"))
+    entry.AddField("year", bibtex.NewBibConst("1974"))
+
+    bib := bibtex.NewBibTex()
+    bib.AddEntry(entry)
+
+    doc := &Document{Ref: bib, Txt: "some extracted text", Path: "/tmp/knuth1974.pdf"}
+    doc.populateFromRef()
+
+    if err := idx.Index(doc.Path, doc); err != nil {
+        t.Fatalf("Index: %s", err)
+    }
+
+    fields, err := idx.Fields()
+    if err != nil {
+        t.Fatalf("Fields: %s", err)
+    }
+    for _, f := range fields {
+        if f == "ref" || strings.HasPrefix(f, "ref.") {
+            t.Errorf("Ref leaked into the index as field %q", f)
+        }
+    }
+}
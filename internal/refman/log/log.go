@@ -0,0 +1,87 @@
+// Package log provides component-filtered debug logging. Every
+// subsystem obtains its own named logger via Component, and a log
+// call is only written if its component name matches one of the
+// comma-separated glob patterns in the DEBUG environment variable
+// (e.g. DEBUG=index.*,pdf.extract, or DEBUG=* for everything).
+package log
+
+import (
+    "fmt"
+    "io"
+    stdlog "log"
+    "os"
+    "path"
+    "strings"
+    "sync"
+)
+
+var (
+    mu       sync.RWMutex
+    patterns []string
+)
+
+func init() {
+    SetPatterns(os.Getenv("DEBUG"))
+}
+
+// SetPatterns replaces the active set of DEBUG patterns, overriding
+// whatever the DEBUG environment variable held at startup. Passing
+// "*" enables every component, matching the -v CLI shortcut.
+func SetPatterns(raw string) {
+    var parsed []string
+    for _, p := range strings.Split(raw, ",") {
+        if p = strings.TrimSpace(p); p != "" {
+            parsed = append(parsed, p)
+        }
+    }
+
+    mu.Lock()
+    patterns = parsed
+    mu.Unlock()
+}
+
+func enabled(name string) bool {
+    mu.RLock()
+    defer mu.RUnlock()
+
+    for _, p := range patterns {
+        if ok, _ := path.Match(p, name); ok {
+            return true
+        }
+    }
+    return false
+}
+
+// Logger writes to stderr, prefixed with its component name, but
+// only when that name matches the active DEBUG patterns.
+type Logger struct {
+    name string
+    std  *stdlog.Logger
+}
+
+// Component returns the named logger for a subsystem, e.g.
+// Component("index.open") or Component("pdf.extract").
+func Component(name string) *Logger {
+    return &Logger{
+        name: name,
+        std:  stdlog.New(os.Stderr, fmt.Sprintf("%s: ", name), stdlog.LstdFlags),
+    }
+}
+
+// SetOutput redirects where l writes once enabled; mainly useful in
+// tests.
+func (l *Logger) SetOutput(w io.Writer) {
+    l.std.SetOutput(w)
+}
+
+func (l *Logger) Printf(format string, args ...interface{}) {
+    if enabled(l.name) {
+        l.std.Printf(format, args...)
+    }
+}
+
+func (l *Logger) Println(args ...interface{}) {
+    if enabled(l.name) {
+        l.std.Println(args...)
+    }
+}
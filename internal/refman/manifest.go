@@ -0,0 +1,84 @@
+package refman
+
+import (
+    "encoding/json"
+    "os"
+    "sync"
+)
+
+// Entry records the source files a Document was built from, so the
+// index can later be rebuilt without the caller re-supplying paths.
+type Entry struct {
+    PDFPath    string `json:"pdfPath"`
+    BibtexPath string `json:"bibtexPath,omitempty"`
+}
+
+// Manifest tracks every entry added to the index, keyed by the same
+// id used in the Bleve index (the entry's absolute PDF path). Its
+// methods are safe for concurrent use, since the HTTP API can serve
+// Put/Delete/Get from multiple request goroutines at once.
+type Manifest struct {
+    path string
+
+    mu      sync.Mutex
+    Entries map[string]Entry `json:"entries"`
+}
+
+// LoadManifest reads the manifest file at path, returning an empty
+// Manifest if one does not exist yet.
+func LoadManifest(path string) (*Manifest, error) {
+    m := &Manifest{path: path, Entries: make(map[string]Entry)}
+
+    f, err := os.Open(path)
+    if err != nil {
+        if os.IsNotExist(err) {
+            return m, nil
+        }
+        return nil, err
+    }
+    defer f.Close()
+
+    if err := json.NewDecoder(f).Decode(m); err != nil {
+        return nil, err
+    }
+    return m, nil
+}
+
+// Save writes the manifest back to disk.
+func (m *Manifest) Save() error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    f, err := os.Create(m.path)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    return json.NewEncoder(f).Encode(m)
+}
+
+// Get returns the entry recorded under id, if any.
+func (m *Manifest) Get(id string) (Entry, bool) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    e, ok := m.Entries[id]
+    return e, ok
+}
+
+// Put records a new entry under id.
+func (m *Manifest) Put(id string, e Entry) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.Entries[id] = e
+}
+
+// Delete removes the entry for id, if any.
+func (m *Manifest) Delete(id string) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    delete(m.Entries, id)
+}
@@ -0,0 +1,31 @@
+package refman
+
+import "path/filepath"
+
+// ParseDocument extracts the text of pdfFile and, if bibtexFile is
+// non-empty, parses the accompanying BibTeX reference, returning a
+// Document ready to be indexed. The Document's Path is always set to
+// the absolute path of pdfFile, which doubles as the index ID.
+func ParseDocument(pdfFile, bibtexFile string) (*Document, error) {
+    absPath, err := filepath.Abs(pdfFile)
+    if err != nil {
+        return nil, err
+    }
+
+    txt, err := ExtractText(pdfFile)
+    if err != nil {
+        return nil, err
+    }
+
+    doc := &Document{Txt: txt, Path: absPath}
+
+    if bibtexFile != "" {
+        doc.Ref, err = ParseBibTex(bibtexFile)
+        if err != nil {
+            return nil, err
+        }
+        doc.populateFromRef()
+    }
+
+    return doc, nil
+}
@@ -0,0 +1,44 @@
+package refman
+
+import (
+    "io"
+    "strings"
+
+    pdfcpuapi "github.com/pdfcpu/pdfcpu/pkg/api"
+
+    "github.com/sug0/refman/internal/refman/log"
+)
+
+var pdfLog = log.Component("pdf.extract")
+
+// ExtractText reads the named PDF file in full and returns the
+// concatenation of the plain text content of every page.
+func ExtractText(pdfFile string) (string, error) {
+    pdfLog.Printf("Opening PDF file: %s\n", pdfFile)
+    ctx, err := pdfcpuapi.ReadContextFile(pdfFile)
+    if err != nil {
+        return "", err
+    }
+
+    if err := ctx.EnsurePageCount(); err != nil {
+        return "", err
+    }
+
+    var txt strings.Builder
+
+    for i := 1; i <= ctx.PageCount; i++ {
+        pdfLog.Printf("Extracting page %d/%d of %s\n", i, ctx.PageCount, pdfFile)
+        plainText, err := ctx.ExtractPageContent(i)
+        if err != nil {
+            return "", err
+        }
+        if plainText == nil {
+            continue
+        }
+        if _, err := io.Copy(&txt, plainText); err != nil {
+            return "", err
+        }
+    }
+
+    return txt.String(), nil
+}